@@ -0,0 +1,168 @@
+package xid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// poolChunkSize is how many random bytes a Generator reads from its
+// entropy source at once, amortizing the cost of that read (a syscall,
+// for the default crypto/rand.Reader) across many IDs instead of paying
+// it on every call that needs random bytes (NextNano,
+// NextWithConcurrency, NewUUIDv7).
+const poolChunkSize = 4096
+
+// randUInt64 returns a random uint64 read from crypto/rand, used to seed
+// counters so they don't start at a predictable value across restarts.
+func randUInt64() uint64 {
+	return randUInt64WithReader(rand.Reader)
+}
+
+// randUInt64WithReader is randUInt64 against an arbitrary entropy source.
+func randUInt64WithReader(r io.Reader) uint64 {
+	b := make([]byte, 8)
+
+	if _, err := io.ReadFull(r, b); err != nil {
+		panic(fmt.Errorf("xid: cannot generate random number: %v", err))
+	}
+
+	return binary.BigEndian.Uint64(b)
+}
+
+// Option configures a Generator.
+type Option func(*Generator)
+
+// WithEntropySource overrides the io.Reader a Generator draws its random
+// bytes from. The default is crypto/rand.Reader; tests and callers with
+// their own userspace RNG (e.g. a ChaCha8-backed math/rand/v2.Rand) can
+// supply a different one.
+func WithEntropySource(r io.Reader) Option {
+	return func(g *Generator) {
+		g.entropy = r
+	}
+}
+
+// Generator produces IDs. The package-level New is a thin wrapper around
+// a shared defaultGenerator; construct a Generator directly when a
+// caller needs its own counter and random pool, or a deterministic
+// entropy source for tests.
+type Generator struct {
+	entropy io.Reader
+	count   uint64
+
+	mu   sync.Mutex
+	pool []byte
+}
+
+// NewGenerator creates a ready-to-use Generator.
+func NewGenerator(opts ...Option) *Generator {
+	g := &Generator{entropy: rand.Reader}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	g.count = randUInt64WithReader(g.entropy)
+
+	return g
+}
+
+// defaultGenerator backs the package-level New function and supplies the
+// random bytes of NewUUIDv7. NewConcurrence and NewWithConcurrence don't
+// use it: they read crypto/rand directly and share the package-level
+// counter.
+var defaultGenerator = NewGenerator()
+
+// fill reads len(dst) random bytes out of the generator's pool,
+// refilling the pool from its entropy source poolChunkSize bytes at a
+// time whenever it runs dry.
+func (g *Generator) fill(dst []byte) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for len(dst) > 0 {
+		if len(g.pool) == 0 {
+			g.pool = make([]byte, poolChunkSize)
+			if _, err := io.ReadFull(g.entropy, g.pool); err != nil {
+				panic(fmt.Errorf("xid: cannot generate random number: %v", err))
+			}
+		}
+
+		n := copy(dst, g.pool)
+		g.pool = g.pool[n:]
+		dst = dst[n:]
+	}
+}
+
+// Next generates an ID using the same ObjectID-compatible layout as
+// NewFromTime, but with a counter owned by this generator instead of the
+// package-level one.
+func (g *Generator) Next() ID {
+	var id ID
+
+	binary.BigEndian.PutUint32(id[0:4], uint32(time.Now().Unix()))
+	copy(id[4:9], processUnique[:])
+
+	c := atomic.AddUint64(&g.count, 1)
+	id[9] = byte(c >> 16)
+	id[10] = byte(c >> 8)
+	id[11] = byte(c)
+
+	return id
+}
+
+// NextNano generates an ID using xid's original nanosecond-precision,
+// non-ObjectID-compatible layout (see NewNanoFromTime), drawing its 6
+// random bytes from the generator's pooled entropy instead of issuing a
+// fresh crypto/rand read per call.
+func (g *Generator) NextNano() ID {
+	var id ID
+
+	binary.BigEndian.PutUint64(id[:], uint64(time.Now().UnixNano()))
+	g.fill(id[6:12])
+
+	return id
+}
+
+// NextN fills dst with freshly generated IDs using Next.
+func (g *Generator) NextN(dst []ID) {
+	for i := range dst {
+		dst[i] = g.Next()
+	}
+}
+
+// NextWithConcurrency generates an ID the way NewWithConcurrence does,
+// starting from NextNano and applying a counter owned by this generator
+// instead of the package-level one, so generators used concurrently
+// don't contend with each other.
+func (g *Generator) NextWithConcurrency(level Concurrency) ID {
+	id := g.NextNano()
+
+	switch level {
+	case NANO:
+		adder := atomic.AddUint64(&g.count, 1)
+		id[6] = (byte(adder<<4) & 0xF0) | (id[6] & 0xF)
+
+	case LOW:
+		adder := atomic.AddUint64(&g.count, 1)
+		id[6] = byte(adder)
+
+	case MEDIUM:
+		adder := atomic.AddUint64(&g.count, 1)
+		id[6] = byte(adder >> 8)
+		id[7] = byte(adder)
+
+	case HIGH:
+		adder := atomic.AddUint64(&g.count, 1)
+		id[6] = byte(adder >> 16)
+		id[7] = byte(adder >> 8)
+		id[8] = byte(adder)
+	}
+
+	return id
+}
@@ -0,0 +1,131 @@
+package xid
+
+import (
+	"testing"
+	"time"
+)
+
+// sequentialReader is a deterministic io.Reader that fills any read with
+// consecutive byte values (wrapping at 256), so tests can assert exactly
+// which bytes a Generator's pool handed out.
+type sequentialReader struct{ n int }
+
+func (r *sequentialReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(r.n)
+		r.n++
+	}
+	return len(p), nil
+}
+
+func TestGeneratorFillRefillsAcrossPoolBoundary(t *testing.T) {
+	src := &sequentialReader{}
+	g := NewGenerator(WithEntropySource(src))
+
+	// NewGenerator already consumed 8 bytes from src to seed g.count.
+	const seeded = 8
+
+	first := make([]byte, poolChunkSize-2)
+	g.fill(first)
+
+	for i, b := range first {
+		if want := byte(seeded + i); b != want {
+			t.Fatalf("fill()[%d] = %d, want %d", i, b, want)
+		}
+	}
+
+	// The next fill should drain the 2 leftover pool bytes, then refill
+	// from src and continue the same sequential stream without gaps.
+	second := make([]byte, 6)
+	g.fill(second)
+
+	for i, b := range second {
+		if want := byte(seeded + len(first) + i); b != want {
+			t.Fatalf("fill() after refill [%d] = %d, want %d", i, b, want)
+		}
+	}
+}
+
+func TestGeneratorNextUsesOwnCounter(t *testing.T) {
+	g := NewGenerator()
+
+	a := g.Next()
+	b := g.Next()
+
+	if a.Counter() == b.Counter() {
+		t.Fatalf("Generator.Next() did not advance its own counter: %v then %v", a, b)
+	}
+}
+
+func TestGeneratorNextNFillsAll(t *testing.T) {
+	g := NewGenerator()
+
+	dst := make([]ID, 16)
+	g.NextN(dst)
+
+	seen := make(map[ID]bool, len(dst))
+	for _, id := range dst {
+		if seen[id] {
+			t.Fatalf("NextN produced a duplicate id: %v", id)
+		}
+		seen[id] = true
+	}
+}
+
+// BenchmarkNewNanoFromTimeLoop measures minting IDs with xid's original
+// nanosecond layout, which reads 6 fresh bytes from crypto/rand on every
+// call.
+func BenchmarkNewNanoFromTimeLoop(b *testing.B) {
+	now := time.Now()
+	for i := 0; i < b.N; i++ {
+		_ = NewNanoFromTime(now)
+	}
+}
+
+// BenchmarkGeneratorNextNano measures the same layout via a Generator,
+// whose pooled entropy amortizes the crypto/rand read across many IDs
+// instead of paying it on every call.
+func BenchmarkGeneratorNextNano(b *testing.B) {
+	g := NewGenerator()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = g.NextNano()
+	}
+}
+
+// BenchmarkGeneratorNextNanoBatch measures NextNano's throughput when
+// called in a tight batch, which is the shape NextN's internal loop
+// takes; compare against BenchmarkNewNanoFromTimeLoop for the
+// order-of-magnitude improvement pooling buys.
+func BenchmarkGeneratorNextNanoBatch(b *testing.B) {
+	g := NewGenerator()
+	dst := make([]ID, 1024)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := range dst {
+			dst[j] = g.NextNano()
+		}
+	}
+}
+
+// BenchmarkNew and BenchmarkGeneratorNextN cover the default,
+// ObjectID-compatible path, which no longer reads crypto/rand per call
+// (see NewFromTime), so its throughput is dominated by time.Now() and
+// the atomic counter rather than pooled entropy.
+func BenchmarkNew(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = New()
+	}
+}
+
+func BenchmarkGeneratorNextN(b *testing.B) {
+	g := NewGenerator()
+	dst := make([]ID, 1024)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		g.NextN(dst)
+	}
+}
@@ -0,0 +1,39 @@
+package xid
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"fmt"
+	"os"
+)
+
+var (
+	// machineID is a 3-byte fingerprint of the host, used by NewClassic to
+	// make IDs minted on different machines collision resistant even when
+	// their random bytes happen to agree.
+	machineID = readMachineID()
+
+	// pid is the process id embedded in IDs minted by NewClassic.
+	pid = uint16(os.Getpid())
+)
+
+// readMachineID asks the platform-specific readPlatformMachineID for a
+// stable host identifier, hashes it with MD5 and keeps the first 3 bytes.
+// When the platform source can't be read (unsupported OS, permission
+// denied, missing file, ...) it falls back to random bytes so callers
+// always get a usable, if no longer cross-host-stable, fingerprint.
+func readMachineID() [3]byte {
+	var id [3]byte
+
+	b, err := readPlatformMachineID()
+	if err != nil || len(b) == 0 {
+		if _, err := rand.Reader.Read(id[:]); err != nil {
+			panic(fmt.Errorf("xid: cannot generate random machine id: %v", err))
+		}
+		return id
+	}
+
+	sum := md5.Sum(b)
+	copy(id[:], sum[:3])
+	return id
+}
@@ -0,0 +1,32 @@
+//go:build darwin
+
+package xid
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+)
+
+// readPlatformMachineID shells out to ioreg for the IOPlatformUUID, the
+// identifier macOS assigns to the hardware at first boot.
+func readPlatformMachineID() ([]byte, error) {
+	out, err := exec.Command("ioreg", "-rd1", "-c", "IOPlatformExpertDevice").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	marker := []byte("\"IOPlatformUUID\" = \"")
+	idx := bytes.Index(out, marker)
+	if idx == -1 {
+		return nil, errors.New("xid: IOPlatformUUID not found in ioreg output")
+	}
+
+	rest := out[idx+len(marker):]
+	end := bytes.IndexByte(rest, '"')
+	if end == -1 {
+		return nil, errors.New("xid: malformed ioreg output")
+	}
+
+	return rest[:end], nil
+}
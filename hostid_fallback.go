@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package xid
+
+import "errors"
+
+// readPlatformMachineID has no implementation on this platform; callers
+// fall back to random bytes.
+func readPlatformMachineID() ([]byte, error) {
+	return nil, errors.New("xid: no machine id source for this platform")
+}
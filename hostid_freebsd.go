@@ -0,0 +1,19 @@
+//go:build freebsd
+
+package xid
+
+import (
+	"bytes"
+	"os/exec"
+)
+
+// readPlatformMachineID reads the kern.hostuuid sysctl, FreeBSD's
+// per-host UUID, via the sysctl binary.
+func readPlatformMachineID() ([]byte, error) {
+	out, err := exec.Command("sysctl", "-n", "kern.hostuuid").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSpace(out), nil
+}
@@ -0,0 +1,12 @@
+//go:build linux
+
+package xid
+
+import "os"
+
+// readPlatformMachineID returns the contents of /etc/machine-id, the
+// systemd-maintained identifier that's stable for the lifetime of the
+// host (or container) and shared by every process running on it.
+func readPlatformMachineID() ([]byte, error) {
+	return os.ReadFile("/etc/machine-id")
+}
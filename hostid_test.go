@@ -0,0 +1,39 @@
+package xid
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestNewClassicRoundTrip(t *testing.T) {
+	before := time.Now()
+	id := NewClassic()
+
+	if id.Time().Unix() != before.Unix() && id.Time().Unix() != before.Unix()+1 {
+		t.Fatalf("Time() = %v, want ~%v", id.Time(), before)
+	}
+
+	if got := id.MachineID(); string(got) != string(machineID[:]) {
+		t.Fatalf("MachineID() = %x, want %x", got, machineID)
+	}
+
+	if got, want := id.Pid(), uint16(os.Getpid()); got != want {
+		t.Fatalf("Pid() = %d, want %d", got, want)
+	}
+}
+
+func TestNewClassicCountersIncrease(t *testing.T) {
+	a := NewClassic()
+	b := NewClassic()
+
+	if a == b {
+		t.Fatalf("NewClassic() returned the same id twice: %v", a)
+	}
+}
+
+func TestMachineIDLength(t *testing.T) {
+	if len(machineID) != 3 {
+		t.Fatalf("machineID length = %d, want 3", len(machineID))
+	}
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package xid
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+)
+
+// readPlatformMachineID reads the MachineGuid value that Windows stores
+// under HKLM\SOFTWARE\Microsoft\Cryptography at install time.
+func readPlatformMachineID() ([]byte, error) {
+	out, err := exec.Command("reg", "query", `HKLM\SOFTWARE\Microsoft\Cryptography`, "/v", "MachineGuid").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	marker := []byte("MachineGuid")
+	idx := bytes.Index(out, marker)
+	if idx == -1 {
+		return nil, errors.New("xid: MachineGuid not found in reg output")
+	}
+
+	fields := bytes.Fields(out[idx:])
+	if len(fields) < 3 {
+		return nil, errors.New("xid: malformed reg output")
+	}
+
+	return fields[len(fields)-1], nil
+}
@@ -1,9 +1,13 @@
 // Package xid is a globally unique id generator
 //
-//   - 6-byte value representing the seconds since the Unix epoch
-//   - 6-byte random value
+//   - 4-byte value representing the seconds since the Unix epoch
+//   - 5-byte process-unique value
+//   - 3-byte incrementing counter, initialized to a random value
 //
-// The binary representation of the id is compatible with Mongo 12 bytes Object IDs.
+// The binary representation of the id produced by New, NewFromTime and NewObjectID is
+// byte-for-byte compatible with Mongo 12 bytes Object IDs. Use NewNanoFromTime for xid's
+// original layout (nanosecond timestamp, 6-byte random value), which sorts more finely
+// within a second but isn't ObjectID compatible.
 // The string representation is using base32 hex (w/o padding) for better space efficiency
 // when stored in that form (20 bytes). The hex variant of base32 is used to retain the
 // sortable property of the id.
@@ -24,7 +28,7 @@
 //   - Base32 hex encoded by default (16 bytes storage when transported as printable string)
 //   - Non configured, you don't need set a unique machine and/or data center id
 //   - K-ordered
-//   - Embedded time with 6 byte precision
+//   - Embedded time, second precision by default (2^16 nanosecond steps via NewNanoFromTime)
 //
 // References:
 //
@@ -37,6 +41,7 @@ import (
 	"crypto/rand"
 	"database/sql/driver"
 	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"sync/atomic"
@@ -74,8 +79,29 @@ var (
 	dec [256]byte
 
 	atomicCount = randUInt64()
+
+	// processUnique is a 5-byte value fixed once per process, used as the
+	// "random" component of a Mongo-compatible ObjectID.
+	processUnique = readProcessUnique()
+
+	// objectIDCounter is the 3-byte (24-bit) counter appended to an
+	// ObjectID, seeded randomly so it doesn't start at the same value on
+	// every process restart.
+	objectIDCounter = uint32(randUInt64())
 )
 
+// readProcessUnique draws the 5 random bytes an ObjectID-compatible ID
+// mixes in alongside its timestamp and counter.
+func readProcessUnique() [5]byte {
+	var b [5]byte
+
+	if _, err := rand.Reader.Read(b[:]); err != nil {
+		panic(fmt.Errorf("xid: cannot generate random number: %v", err))
+	}
+
+	return b
+}
+
 func init() {
 	for i := 0; i < len(dec); i++ {
 		dec[i] = 0xFF
@@ -85,21 +111,58 @@ func init() {
 	}
 }
 
-// New generates a globally unique ID
+// New generates a globally unique ID using the package's default
+// Generator.
 func New() ID {
-	return NewFromTime(time.Now())
+	return defaultGenerator.Next()
 }
 
-// Generates new Global ID with concurrence atomic counter
+// NewConcurrence generates an ID using xid's original nanosecond layout
+// (see NewNanoFromTime) with a LOW-mode atomic counter applied on top;
+// see NewWithConcurrence for what each Concurrency level trades away.
 func NewConcurrence() ID {
 	return NewWithConcurrence(LOW, time.Now())
 }
 
-// Create ID using a time instance.
-// Apply the 3th SOLID principle: Liskov substitution
+// NewFromTime creates an ID for the given time instance. Its 12 bytes are
+// byte-for-byte compatible with a Mongo ObjectID: a 4-byte big-endian
+// Unix timestamp (second precision), a 5-byte value fixed once per
+// process, and a 3-byte big-endian counter incremented atomically. This
+// is the layout the package doc has always promised; use NewObjectID as
+// a more explicit spelling of the same call, or NewNanoFromTime for
+// xid's original, finer-grained (2^16 nanosecond steps) but
+// non-ObjectID-compatible layout.
 func NewFromTime(t time.Time) ID {
 	var id ID
 
+	binary.BigEndian.PutUint32(id[0:4], uint32(t.Unix()))
+	copy(id[4:9], processUnique[:])
+
+	c := atomic.AddUint32(&objectIDCounter, 1)
+	id[9] = byte(c >> 16)
+	id[10] = byte(c >> 8)
+	id[11] = byte(c)
+
+	return id
+}
+
+// NewObjectID is an explicit alias for NewFromTime, for callers who want
+// their intent - producing a value a Mongo driver will accept as an
+// ObjectID - to read clearly at the call site.
+func NewObjectID(t time.Time) ID {
+	return NewFromTime(t)
+}
+
+// NewNanoFromTime creates an ID using xid's original layout: the first 6
+// bytes are the high 48 bits of t's UnixNano big-endian value, and the
+// last 6 bytes are random. This layout is NOT Mongo ObjectID compatible
+// (an ObjectID's first 4 bytes are whole seconds, not partial
+// nanoseconds) but orders ids in time steps of 2^16 nanoseconds
+// (about 65µs) instead of whole seconds; prefer
+// NewFromTime/NewObjectID when interoperating with a Mongo driver.
+func NewNanoFromTime(t time.Time) ID {
+	var id ID
+
 	// Timestamp, 6 bytes, big endian
 	binary.BigEndian.PutUint64(id[:], uint64(t.UnixNano()))
 
@@ -112,67 +175,84 @@ func NewFromTime(t time.Time) ID {
 	return id
 }
 
-func NewWithConcurrence(currencyLevel Concurrency, t time.Time) ID {
-	id := NewFromTime(t)
-
-	switch currencyLevel {
-
-	case NANO:
-		ApplyNanoConcurrence(id)
+// ObjectIDHex returns the 24-character lowercase hex representation of
+// the id, the string form Mongo drivers use for an ObjectID.
+func (id ID) ObjectIDHex() string {
+	return hex.EncodeToString(id[:])
+}
 
-	case LOW:
-		ApplyLowConcurrence(id)
+// NewClassic generates an ID using the layout popularized by rs/xid: a
+// 4-byte big-endian seconds-since-epoch timestamp, a 3-byte machine ID,
+// a 2-byte process ID and a 3-byte atomic counter. Unlike New, two
+// processes on different hosts can't produce the same ID for the same
+// second, which makes this layout the right choice when IDs from many
+// hosts are merged into a single sorted stream.
+func NewClassic() ID {
+	var id ID
 
-	case MEDIUM:
-		ApplyMediumConcurrence(id)
+	binary.BigEndian.PutUint32(id[0:4], uint32(time.Now().Unix()))
+	copy(id[4:7], machineID[:])
+	binary.BigEndian.PutUint16(id[7:9], pid)
 
-	case HIGH:
-		ApplyHighConcurrence(id)
-	}
+	adder := atomic.AddUint64(&atomicCount, 1)
+	id[9] = byte(adder >> 16)
+	id[10] = byte(adder >> 8)
+	id[11] = byte(adder)
 
 	return id
 }
 
-// Apply concurrence counter to ID.
-// The nano implementation only accept 2^4 unique IDs in 2^16 nanoseconds and reduce the random bytes to 44 bits
-func ApplyNanoConcurrence(id ID) {
-	adder := atomic.AddUint64(&atomicCount, 1)
-	id[6] = (byte(adder << 4) & 0xF0) | (id[6] & 0xF)
+// MachineID returns the 3-byte machine fingerprint embedded in an ID
+// created with NewClassic. Calling it on an ID built with a different
+// layout returns meaningless bytes.
+func (id ID) MachineID() []byte {
+	return id[4:7]
 }
 
-// Apply concurrence counter to ID.
-// The low implementation only accept 2^8 unique IDs in 2^16 nanoseconds and reduce the random bytes to 40 bits
-func ApplyLowConcurrence(id ID) {
-	adder := atomic.AddUint64(&atomicCount, 1)
-	id[6] = byte(adder)
+// Pid returns the 2-byte process id embedded in an ID created with
+// NewClassic. Calling it on an ID built with a different layout returns
+// a meaningless value.
+func (id ID) Pid() uint16 {
+	return binary.BigEndian.Uint16(id[7:9])
 }
 
-// Apply concurrence counter to ID.
-// The medium implementation only accept 2^16 unique IDs in 2^16 nanoseconds and reduce the random bytes to 32 bits
-func ApplyMediumConcurrence(id ID) {
-	adder := atomic.AddUint64(&atomicCount, 1)
-	id[6] = byte(adder >> 8)
-	id[7] = byte(adder)
-}
+// NewWithConcurrence generates an ID using xid's original nanosecond
+// layout (see NewNanoFromTime) and overwrites part of its random tail
+// with a package-level atomic counter, trading some of that tail's
+// entropy for a guarantee that calls sharing the same timestamp (one tick
+// of 2^16 nanoseconds) don't collide. currencyLevel controls how many of
+// the 6 random bytes the counter replaces:
+//
+//   - NANO: 4 bits, 2^4 unique ids per 2^16 nanoseconds, 44 bits of randomness left
+//   - LOW: 1 byte, 2^8 unique ids per 2^16 nanoseconds, 40 bits of randomness left
+//   - MEDIUM: 2 bytes, 2^16 unique ids per 2^16 nanoseconds, 32 bits of randomness left
+//   - HIGH: 3 bytes, 2^24 unique ids per 2^16 nanoseconds, 24 bits of randomness left
+func NewWithConcurrence(currencyLevel Concurrency, t time.Time) ID {
+	id := NewNanoFromTime(t)
 
-// Apply concurrence counter to ID.
-// The high implementation only accept 2^24 unique IDs in 2^16 nanoseconds and reduce the random bytes to 24 bits
-func ApplyHighConcurrence(id ID) {
-	adder := atomic.AddUint64(&atomicCount, 1)
-	id[6] = byte(adder >> 16)
-	id[7] = byte(adder >> 8)
-	id[8] = byte(adder)
-}
+	switch currencyLevel {
+
+	case NANO:
+		adder := atomic.AddUint64(&atomicCount, 1)
+		id[6] = (byte(adder<<4) & 0xF0) | (id[6] & 0xF)
 
-// randInt generates a random uint16
-func randUInt64() uint64 {
-	b := make([]byte, 2)
+	case LOW:
+		adder := atomic.AddUint64(&atomicCount, 1)
+		id[6] = byte(adder)
 
-	if _, err := rand.Reader.Read(b); err != nil {
-		panic(fmt.Errorf("xid: cannot generate random number: %v", err))
+	case MEDIUM:
+		adder := atomic.AddUint64(&atomicCount, 1)
+		id[6] = byte(adder >> 8)
+		id[7] = byte(adder)
+
+	case HIGH:
+		adder := atomic.AddUint64(&atomicCount, 1)
+		id[6] = byte(adder >> 16)
+		id[7] = byte(adder >> 8)
+		id[8] = byte(adder)
 	}
 
-	return uint64(b[0]) << 32 | uint64(b[1]) << 16 | uint64(b[2]) << 8 | uint64(b[3])
+	return id
 }
 
 // FromString reads an ID from its string representation
@@ -250,22 +330,39 @@ func decode(id *ID, src []byte) {
 	id[11] = dec[src[17]]<<6 | dec[src[18]]<<1 | dec[src[19]]>>4
 }
 
-// Time returns the timestamp part of the id.
-// It's a runtime error to call this method with an invalid id.
+// Time returns the timestamp part of the id, decoding the 4-byte
+// big-endian seconds field at id[0:4]. This matches the default,
+// ObjectID-compatible layout produced by New, NewFromTime, NewObjectID
+// and NewClassic. Use NanoTime for an id produced by NewNanoFromTime or
+// NewWithConcurrence, whose timestamp field is laid out differently.
 func (id ID) Time() time.Time {
-	// First 6 bytes of ObjectId is 64-bit big-endian nanos from epoch.
+	return time.Unix(int64(binary.BigEndian.Uint32(id[0:4])), 0)
+}
+
+// Counter returns the 3-byte incrementing counter embedded in an id
+// built with the default, ObjectID-compatible layout. Use NanoCounter
+// for an id produced by NewNanoFromTime.
+func (id ID) Counter() uint64 {
+	return uint64(id[9])<<16 | uint64(id[10])<<8 | uint64(id[11])
+}
+
+// NanoTime returns the timestamp part of an id produced by
+// NewNanoFromTime, whose first 6 bytes are the high 48 bits of a
+// nanosecond-precision big-endian Unix timestamp.
+func (id ID) NanoTime() time.Time {
+	// First 6 bytes are the high 48 bits of a 64-bit big-endian nanos value.
 	nowBytes := make([]byte, 8)
 	copy(nowBytes[0:6], id[0:6])
 	nanos := int64(binary.BigEndian.Uint64(nowBytes))
 	return time.Unix(0, nanos)
 }
 
-// Counter returns the random value part of the id.
-// It's a runtime error to call this method with an invalid id.
-func (id ID) Counter() uint64 {
+// NanoCounter returns the 6 random bytes of an id produced by
+// NewNanoFromTime (or the random bytes left over after NewWithConcurrence
+// applies its counter to a subset of them).
+func (id ID) NanoCounter() uint64 {
 	b := id[6:]
-	// Counter is stored as big-endian 6-byte value
-	return uint64(uint64(b[0])<<40 | uint64(b[1])<<32 | uint64(b[2])<<24 | uint64(b[3])<<16 | uint64(b[4])<<8 | uint64(b[5]))
+	return uint64(b[0])<<40 | uint64(b[1])<<32 | uint64(b[2])<<24 | uint64(b[3])<<16 | uint64(b[4])<<8 | uint64(b[5])
 }
 
 // Value implements the driver.Valuer interface.
@@ -0,0 +1,73 @@
+package xid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewFromTimeRoundTrip(t *testing.T) {
+	now := time.Now()
+	id := NewFromTime(now)
+
+	got := id.Time()
+	if got.Unix() != now.Unix() {
+		t.Fatalf("Time() = %v, want second-truncated %v", got, now)
+	}
+
+	if id.Counter() == 0 {
+		t.Fatalf("Counter() = 0, want a non-zero incrementing value")
+	}
+}
+
+func TestNewObjectIDIsObjectIDCompatible(t *testing.T) {
+	now := time.Now()
+	id := NewObjectID(now)
+
+	if id.Time().Unix() != now.Unix() {
+		t.Fatalf("Time() = %v, want second-truncated %v", id.Time(), now)
+	}
+
+	hexID := id.ObjectIDHex()
+	if len(hexID) != 24 {
+		t.Fatalf("ObjectIDHex() length = %d, want 24", len(hexID))
+	}
+}
+
+func TestNewNanoFromTimeRoundTrip(t *testing.T) {
+	now := time.Now()
+	id := NewNanoFromTime(now)
+
+	got := id.NanoTime()
+	if got.UnixNano()>>16 != now.UnixNano()>>16 {
+		t.Fatalf("NanoTime() = %v, want ~%v (48-bit truncated)", got, now)
+	}
+}
+
+func TestNewWithConcurrenceAppliesCounter(t *testing.T) {
+	now := time.Now()
+
+	a := NewWithConcurrence(HIGH, now)
+	b := NewWithConcurrence(HIGH, now)
+
+	if a == b {
+		t.Fatalf("NewWithConcurrence returned identical ids for the same instant: %v", a)
+	}
+
+	if a.NanoTime().UnixNano()>>16 != now.UnixNano()>>16 {
+		t.Fatalf("NanoTime() = %v, want ~%v (48-bit truncated)", a.NanoTime(), now)
+	}
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	id := NewFromTime(time.Now())
+
+	s := id.String()
+	got, err := FromString(s)
+	if err != nil {
+		t.Fatalf("FromString(%q) error: %v", s, err)
+	}
+
+	if got != id {
+		t.Fatalf("FromString(%q) = %v, want %v", s, got, id)
+	}
+}
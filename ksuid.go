@@ -0,0 +1,208 @@
+package xid
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+const (
+	ksidEncodedLen = 27 // string encoded len
+	ksidRawLen     = 20 // binary raw len
+	ksidPayloadLen = 16 // payload len, everything but the timestamp
+
+	// ksidEpoch is KSUID's custom epoch, 2014-05-13T16:53:20Z (Unix epoch
+	// + 1400000000), which buys a few more decades before the 32-bit
+	// seconds field wraps compared to counting from 1970.
+	ksidEpoch = 1400000000
+
+	// ksidBase62Alphabet is the base62 alphabet KSUID uses for its string
+	// form. Its digits are in ascending ASCII order, so together with the
+	// fixed 27-digit, zero-padded width, string order matches byte order.
+	ksidBase62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// ErrInvalidKSID is returned when trying to unmarshal an invalid KSID
+var ErrInvalidKSID = errors.New("xid: invalid KSID")
+
+// KSID is a 20-byte sibling of ID compatible with Segment's KSUID: a
+// 4-byte big-endian seconds-since-ksidEpoch timestamp followed by 16
+// bytes of payload, giving 128 bits of collision resistance per second
+// instead of ID's 48.
+type KSID [ksidRawLen]byte
+
+// NewKSID generates a KSID using the current time and a freshly drawn
+// random payload. Prefer a KSIDGenerator when minting many KSIDs from
+// the same process: it guarantees strict ordering within a second, which
+// independent calls to NewKSID cannot.
+func NewKSID() KSID {
+	var id KSID
+
+	binary.BigEndian.PutUint32(id[0:4], uint32(time.Now().Unix()-ksidEpoch))
+	if _, err := rand.Reader.Read(id[4:ksidRawLen]); err != nil {
+		panic(fmt.Errorf("xid: cannot generate random number: %v", err))
+	}
+
+	return id
+}
+
+// Time returns the timestamp part of the id.
+func (id KSID) Time() time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint32(id[0:4]))+ksidEpoch, 0)
+}
+
+// Payload returns the 16-byte payload part of the id.
+func (id KSID) Payload() []byte {
+	return id[4:ksidRawLen]
+}
+
+// String returns the id as a fixed-width, 27-character base62 string,
+// left-padded with '0' so short encodings stay sortable alongside full
+// width ones.
+func (id KSID) String() string {
+	n := new(big.Int).SetBytes(id[:])
+	base := big.NewInt(62)
+	mod := new(big.Int)
+
+	digits := make([]byte, ksidEncodedLen)
+	for i := ksidEncodedLen - 1; i >= 0; i-- {
+		n.DivMod(n, base, mod)
+		digits[i] = ksidBase62Alphabet[mod.Int64()]
+	}
+
+	return string(digits)
+}
+
+// MarshalText implements encoding/text TextMarshaler interface
+func (id KSID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding/text TextUnmarshaler interface
+func (id *KSID) UnmarshalText(text []byte) error {
+	if len(text) != ksidEncodedLen {
+		return ErrInvalidKSID
+	}
+
+	n := new(big.Int)
+	base := big.NewInt(62)
+	digit := new(big.Int)
+	for _, c := range text {
+		v := ksidBase62Index(c)
+		if v < 0 {
+			return ErrInvalidKSID
+		}
+		digit.SetInt64(int64(v))
+		n.Mul(n, base)
+		n.Add(n, digit)
+	}
+
+	b := n.Bytes()
+	if len(b) > ksidRawLen {
+		return ErrInvalidKSID
+	}
+
+	var raw KSID
+	copy(raw[ksidRawLen-len(b):], b)
+	*id = raw
+
+	return nil
+}
+
+// ksidBase62Index returns the numeric value of a base62 digit, or -1 if c
+// isn't one.
+func ksidBase62Index(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 36
+	default:
+		return -1
+	}
+}
+
+// Value implements the driver.Valuer interface.
+func (id KSID) Value() (driver.Value, error) {
+	return id.String(), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (id *KSID) Scan(value interface{}) (err error) {
+	switch val := value.(type) {
+	case string:
+		return id.UnmarshalText([]byte(val))
+	case []byte:
+		if len(val) == ksidRawLen {
+			copy(id[:], val)
+			return nil
+		}
+		return id.UnmarshalText(val)
+	default:
+		return fmt.Errorf("xid: scanning unsupported type: %T", value)
+	}
+}
+
+// KSIDGenerator owns its own timestamp and payload state so it can
+// guarantee strict monotonicity within the same second: treating the
+// 16-byte payload as a 128-bit big-endian integer, a call to Next that
+// lands in the same second as the previous one increments that integer
+// by one instead of drawing fresh randomness; a call in a new second
+// samples 16 new random bytes. This gives k-ordering even under bursts,
+// at no cost to the per-second collision resistance a single KSID
+// already has. Next is not lock-free: concurrent callers are serialized
+// on the generator's mutex.
+type KSIDGenerator struct {
+	mu      sync.Mutex
+	lastSec uint32
+	payload [ksidPayloadLen]byte
+	seeded  bool
+}
+
+// NewKSIDGenerator creates a ready-to-use KSIDGenerator.
+func NewKSIDGenerator() *KSIDGenerator {
+	return &KSIDGenerator{}
+}
+
+// Next returns the next, monotonically increasing KSID from the
+// generator.
+func (g *KSIDGenerator) Next() KSID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	sec := uint32(time.Now().Unix() - ksidEpoch)
+
+	if g.seeded && sec == g.lastSec {
+		incBigEndian(g.payload[:])
+	} else {
+		if _, err := rand.Reader.Read(g.payload[:]); err != nil {
+			panic(fmt.Errorf("xid: cannot generate random number: %v", err))
+		}
+		g.lastSec = sec
+		g.seeded = true
+	}
+
+	var id KSID
+	binary.BigEndian.PutUint32(id[0:4], sec)
+	copy(id[4:ksidRawLen], g.payload[:])
+
+	return id
+}
+
+// incBigEndian increments b, treated as a big-endian unsigned integer,
+// by one, carrying across byte boundaries.
+func incBigEndian(b []byte) {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package xid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKSIDStringRoundTrip(t *testing.T) {
+	id := NewKSID()
+
+	s := id.String()
+	if len(s) != ksidEncodedLen {
+		t.Fatalf("String() length = %d, want %d", len(s), ksidEncodedLen)
+	}
+
+	var got KSID
+	if err := got.UnmarshalText([]byte(s)); err != nil {
+		t.Fatalf("UnmarshalText(%q) error: %v", s, err)
+	}
+
+	if got != id {
+		t.Fatalf("UnmarshalText(String()) = %v, want %v", got, id)
+	}
+}
+
+func TestKSIDTimeRoundTrip(t *testing.T) {
+	now := time.Now()
+	id := NewKSID()
+
+	if id.Time().Unix() != now.Unix() && id.Time().Unix() != now.Unix()+1 {
+		t.Fatalf("Time() = %v, want ~%v", id.Time(), now)
+	}
+}
+
+func TestKSIDUnmarshalTextRejectsInvalid(t *testing.T) {
+	var id KSID
+
+	if err := id.UnmarshalText([]byte("too-short")); err != ErrInvalidKSID {
+		t.Fatalf("UnmarshalText(short) error = %v, want ErrInvalidKSID", err)
+	}
+}
+
+func TestKSIDGeneratorMonotonic(t *testing.T) {
+	g := NewKSIDGenerator()
+
+	prev := g.Next()
+	for i := 0; i < 1000; i++ {
+		next := g.Next()
+		if next.String() <= prev.String() {
+			t.Fatalf("KSIDGenerator produced non-increasing ids: %v then %v", prev, next)
+		}
+		prev = next
+	}
+}
+
+func TestIncBigEndianCarries(t *testing.T) {
+	b := []byte{0x00, 0xFF, 0xFF}
+	incBigEndian(b)
+
+	want := []byte{0x01, 0x00, 0x00}
+	for i := range b {
+		if b[i] != want[i] {
+			t.Fatalf("incBigEndian carried incorrectly: got %x, want %x", b, want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package xid
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidUUID is returned when trying to parse a malformed UUIDv7
+// string or byte slice.
+var ErrInvalidUUID = errors.New("xid: invalid uuid")
+
+// UUIDv7 encodes id as an RFC 9562 UUIDv7 value: a 48-bit big-endian Unix
+// millisecond timestamp, the 0111 version nibble, 12 bits of rand_a, the
+// 10 variant bits and 62 bits of rand_b. Only id[0:4] (seconds) and
+// id[6:12] (its counter/random tail) feed the conversion; id[4:6] - part
+// of the ObjectID-compatible layout's process-unique value - isn't read.
+// id[6:12]'s 48 bits are enough to fill all of rand_a but only the top 36
+// bits of rand_b, so rand_b's low 26 bits are always zero; see FromUUIDv7
+// for the reverse conversion, which recovers id[0:4] and id[6:12]
+// losslessly but leaves id[4:6] zeroed. Because this reuses whatever
+// entropy id already carries - which, for the default ObjectID-compatible
+// layout, is the process-unique value's tail plus a slowly-incrementing
+// counter rather than fresh randomness - prefer NewUUIDv7 when
+// unguessability matters.
+func (id ID) UUIDv7() [16]byte {
+	var u [16]byte
+
+	ms := uint64(id.Time().UnixMilli())
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	u[2] = byte(ms >> 24)
+	u[3] = byte(ms >> 16)
+	u[4] = byte(ms >> 8)
+	u[5] = byte(ms)
+
+	u[6] = 0x70 | (id[6] >> 4)
+	u[7] = id[6]<<4 | id[7]>>4
+	u[8] = 0x80 | (id[7]&0x0F)<<2 | id[8]>>6
+	u[9] = id[8]<<2 | id[9]>>6
+	u[10] = id[9]<<2 | id[10]>>6
+	u[11] = id[10]<<2 | id[11]>>6
+	u[12] = id[11] << 2
+
+	return u
+}
+
+// FromUUIDv7 decodes a UUIDv7 value back into an ID using the default,
+// ObjectID-compatible layout (see ID.Time/ID.Counter), the true inverse
+// of ID.UUIDv7's bit packing. The conversion is lossy: a UUIDv7 carries a
+// millisecond timestamp plus 74 bits of randomness, while id only has
+// room for a second-precision timestamp (id[0:4]) and 48 bits of
+// random/counter data (id[6:12]), so sub-second precision and the low
+// bits of rand_b are discarded; id[4:6] isn't part of this conversion at
+// all and is left zeroed, since ID.UUIDv7 never reads it either.
+func FromUUIDv7(u [16]byte) ID {
+	var id ID
+
+	ms := uint64(u[0])<<40 | uint64(u[1])<<32 | uint64(u[2])<<24 | uint64(u[3])<<16 | uint64(u[4])<<8 | uint64(u[5])
+	seconds := uint32(time.UnixMilli(int64(ms)).Unix())
+	binary.BigEndian.PutUint32(id[0:4], seconds)
+
+	id[6] = (u[6]&0x0F)<<4 | u[7]>>4
+	id[7] = (u[7]&0x0F)<<4 | (u[8]&0x3F)>>2
+	id[8] = (u[8]&0x03)<<6 | u[9]>>2
+	id[9] = (u[9]&0x03)<<6 | u[10]>>2
+	id[10] = (u[10]&0x03)<<6 | u[11]>>2
+	id[11] = (u[11]&0x03)<<6 | u[12]>>2
+
+	return id
+}
+
+// NewUUIDv7 generates a fresh, fully-random UUIDv7 and returns its
+// canonical xxxxxxxx-xxxx-7xxx-yxxx-xxxxxxxxxxxx string form. Unlike
+// ID.UUIDv7, which maps an existing 96-bit ID onto the 128-bit UUIDv7
+// layout and so is limited to whatever entropy that ID carries,
+// NewUUIDv7 draws 10 fresh bytes from the package's default Generator
+// pool for rand_a/rand_b, so consecutive values aren't predictable from
+// one another.
+func NewUUIDv7() string {
+	var u [16]byte
+
+	ms := uint64(time.Now().UnixMilli())
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	u[2] = byte(ms >> 24)
+	u[3] = byte(ms >> 16)
+	u[4] = byte(ms >> 8)
+	u[5] = byte(ms)
+
+	var r [10]byte
+	defaultGenerator.fill(r[:])
+
+	u[6] = 0x70 | (r[0] & 0x0F)
+	u[7] = r[1]
+	u[8] = 0x80 | (r[2] & 0x3F)
+	copy(u[9:16], r[3:10])
+
+	return formatUUIDv7(u)
+}
+
+// UUIDv7String returns the canonical dashed, lowercase hex representation
+// of the id's UUIDv7 encoding.
+func (id ID) UUIDv7String() string {
+	return formatUUIDv7(id.UUIDv7())
+}
+
+// formatUUIDv7 renders a 16-byte UUIDv7 value as its canonical dashed,
+// lowercase hex string.
+func formatUUIDv7(u [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:36], u[10:16])
+
+	return string(buf[:])
+}
+
+// parseUUIDv7String decodes a canonical, dashed-or-not UUID string into
+// its 16 raw bytes.
+func parseUUIDv7String(s string) ([16]byte, error) {
+	var u [16]byte
+
+	s = strings.ReplaceAll(s, "-", "")
+	if len(s) != 32 {
+		return u, ErrInvalidUUID
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return u, ErrInvalidUUID
+	}
+	copy(u[:], b)
+
+	return u, nil
+}
+
+// UUIDv7Value wraps an ID so it can be stored in, and scanned back from,
+// a database column typed uuid: it round-trips through the UUIDv7
+// encoding instead of the raw 12-byte layout ID.Value/Scan use.
+type UUIDv7Value ID
+
+// Value implements the driver.Valuer interface.
+func (v UUIDv7Value) Value() (driver.Value, error) {
+	return ID(v).UUIDv7String(), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (v *UUIDv7Value) Scan(value interface{}) error {
+	switch val := value.(type) {
+	case string:
+		u, err := parseUUIDv7String(val)
+		if err != nil {
+			return err
+		}
+		*v = UUIDv7Value(FromUUIDv7(u))
+		return nil
+	case []byte:
+		u, err := parseUUIDv7String(string(val))
+		if err != nil {
+			return err
+		}
+		*v = UUIDv7Value(FromUUIDv7(u))
+		return nil
+	default:
+		return fmt.Errorf("xid: scanning unsupported type: %T", value)
+	}
+}
@@ -0,0 +1,81 @@
+package xid
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUUIDv7RoundTrip(t *testing.T) {
+	id := NewFromTime(time.Now())
+
+	u := id.UUIDv7()
+	got := FromUUIDv7(u)
+
+	// id's second-precision timestamp (id[0:4]) and all 48 bits of its
+	// counter/random tail (id[6:12]) fit losslessly into a UUIDv7 - they
+	// only share the top 36 of rand_b's 62 bits, the rest of which is
+	// documented zero padding - so those bytes should round-trip exactly.
+	// id[4:6] is never read by UUIDv7 nor written by FromUUIDv7, so it's
+	// excluded from the comparison.
+	if !bytes.Equal(got[0:4], id[0:4]) {
+		t.Fatalf("FromUUIDv7(id.UUIDv7())[0:4] = %x, want %x", got[0:4], id[0:4])
+	}
+	if !bytes.Equal(got[6:12], id[6:12]) {
+		t.Fatalf("FromUUIDv7(id.UUIDv7())[6:12] = %x, want %x", got[6:12], id[6:12])
+	}
+}
+
+func TestUUIDv7StringFormat(t *testing.T) {
+	s := NewFromTime(time.Now()).UUIDv7String()
+
+	if len(s) != 36 {
+		t.Fatalf("UUIDv7String() length = %d, want 36", len(s))
+	}
+
+	parts := strings.Split(s, "-")
+	lens := []int{8, 4, 4, 4, 12}
+	for i, p := range parts {
+		if len(p) != lens[i] {
+			t.Fatalf("UUIDv7String() = %q, segment %d has length %d, want %d", s, i, len(p), lens[i])
+		}
+	}
+
+	if parts[2][0] != '7' {
+		t.Fatalf("UUIDv7String() = %q, version nibble = %q, want '7'", s, parts[2][0])
+	}
+}
+
+func TestNewUUIDv7IsUnpredictable(t *testing.T) {
+	a := NewUUIDv7()
+	b := NewUUIDv7()
+
+	if a == b {
+		t.Fatalf("NewUUIDv7() returned the same value twice: %q", a)
+	}
+
+	// rand_a/rand_b should differ beyond a predictable +1 in the last byte.
+	if a[:len(a)-2] == b[:len(b)-2] {
+		t.Fatalf("NewUUIDv7() values only differ in their last byte, entropy looks reused: %q vs %q", a, b)
+	}
+}
+
+func TestUUIDv7ValueRoundTrip(t *testing.T) {
+	id := NewFromTime(time.Now())
+	v := UUIDv7Value(id)
+
+	stored, err := v.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	var scanned UUIDv7Value
+	if err := scanned.Scan(stored); err != nil {
+		t.Fatalf("Scan(%v) error: %v", stored, err)
+	}
+
+	if ID(scanned).Time().Unix() != id.Time().Unix() {
+		t.Fatalf("round-tripped UUIDv7Value.Time() = %v, want %v", ID(scanned).Time(), id.Time())
+	}
+}